@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"log"
 	"slices"
 	"time"
 
+	"upsetter/notify"
+	"upsetter/promquery"
 	"upsetter/pushgateway"
 	"upsetter/tracking"
 )
@@ -14,12 +18,18 @@ const defaultRefresh = "20s"
 const defaultTTL = "24h"
 const defaultURL = "http://localhost:9091"
 
+// alertEndsAtMargin is how far past now a firing alert's endsAt is set, so
+// Alertmanager keeps it firing until the next transition refreshes it.
+const alertEndsAtMargin = 5 * time.Minute
+
 func main() {
 	log.SetFlags(0)
 
 	refreshFlag := flag.String("refresh", defaultRefresh, "Refresh period")
 	ttlFlag := flag.String("ttl", defaultTTL, "Group TTL")
 	urlFlag := flag.String("url", defaultURL, "Pushgateway URL")
+	prometheusFlag := flag.String("prometheus", "", "Prometheus URL, for grounding the up/down decision in server-side truth")
+	alertmanagerFlag := flag.String("alertmanager", "", "Alertmanager URL, for pushing alerts on up/down transitions")
 	flag.Parse()
 
 	refreshPeriod, err := time.ParseDuration(*refreshFlag)
@@ -31,65 +41,200 @@ func main() {
 		log.Fatalf("Error parsing group TTL: %v", err)
 	}
 
-	client := pushgateway.NewPushgateway(*urlFlag)
+	a := &app{
+		client:        pushgateway.NewPushgateway(*urlFlag),
+		groupTTL:      groupTTL,
+		refreshPeriod: refreshPeriod,
+	}
+	if *prometheusFlag != "" {
+		promClient, err := promquery.NewClient(*prometheusFlag)
+		if err != nil {
+			log.Fatalf("Error configuring Prometheus client: %v", err)
+		}
+		a.promClient = &promClient
+	}
+	if *alertmanagerFlag != "" {
+		notifyClient, err := notify.NewClient(*alertmanagerFlag)
+		if err != nil {
+			log.Fatalf("Error configuring Alertmanager client: %v", err)
+		}
+		a.notifyClient = &notifyClient
+	}
+
 	states := map[string]*tracking.GroupState{}
 
 	for _ = range time.Tick(refreshPeriod) {
-		groups, err := client.QueryMetrics()
-		if err != nil {
-			log.Printf("Error querying metrics: %v", err)
+		a.refresh(states)
+	}
+}
+
+// app holds the configured clients and settings driving each refresh tick.
+type app struct {
+	client        pushgateway.Pushgateway
+	promClient    *promquery.Client
+	notifyClient  *notify.Client
+	groupTTL      time.Duration
+	refreshPeriod time.Duration
+}
+
+// refresh runs a single refresh tick, bounded by a context derived from
+// refreshPeriod so a slow Pushgateway can't stall the next tick.
+func (a *app) refresh(states map[string]*tracking.GroupState) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.refreshPeriod)
+	defer cancel()
+
+	groups, warnings, err := a.client.QueryMetricsContext(ctx)
+	if err != nil {
+		log.Printf("Error querying metrics: %v", err)
+		return
+	}
+	for _, warning := range warnings {
+		log.Printf("Warning querying metrics: %v", warning)
+	}
+
+	receivedKeys := make([]string, 0, len(groups))
+	expirationTime := time.Now().Add(-a.groupTTL)
+
+	for _, group := range groups {
+		if !group.LabelNamesMatch("job", "instance") {
 			continue
 		}
 
-		receivedKeys := make([]string, 0, len(groups))
-		expirationTime := time.Now().Add(-groupTTL)
+		key := group.Key()
+		receivedKeys = append(receivedKeys, key)
+		metrics := group.Metrics.Filter("up", "push_time_seconds", "push_failure_time_seconds")
+		timestamp := metrics.MinTimestamp()
 
-		for _, group := range groups {
-			if !group.LabelNamesMatch("job", "instance") {
-				continue
+		state, ok := states[key]
+		if !ok {
+			states[key] = tracking.NewGroupState(timestamp)
+			log.Printf("Group added: %v", key)
+			continue
+		}
+
+		if group.Metrics.MaxTimestamp().Before(expirationTime) {
+			delete(states, key)
+			log.Printf("Group expired: %v", key)
+			err := a.client.DeleteContext(ctx, key)
+			if err != nil {
+				log.Printf("Error deleting %s: %v", key, err)
 			}
+			lastSeen := group.Metrics.MaxTimestamp()
+			a.notifyAlertmanager(ctx, key, group, fmt.Sprintf("Group %s expired, last seen at %s", key, lastSeen.Format(time.RFC3339)), true)
+			continue
+		}
 
-			key := group.Key()
-			receivedKeys = append(receivedKeys, key)
-			metrics := group.Metrics.Filter("up", "push_time_seconds", "push_failure_time_seconds")
-			timestamp := metrics.MinTimestamp()
+		a.updateState(ctx, key, group, state, timestamp)
+	}
 
-			state, ok := states[key]
-			if !ok {
-				states[key] = tracking.NewGroupState(timestamp)
-				log.Printf("Group added: %v", key)
-				continue
-			}
+	for key := range states {
+		if !slices.Contains(receivedKeys, key) {
+			delete(states, key)
+			log.Printf("Group removed: %v", key)
+		}
+	}
+}
 
-			if group.Metrics.MaxTimestamp().Before(expirationTime) {
-				delete(states, key)
-				log.Printf("Group expired: %v", key)
-				err := client.Delete(key)
-				if err != nil {
-					log.Printf("Error deleting %s: %v", key, err)
-				}
-				continue
-			}
+// defaultFreshnessMultiplier times refreshPeriod bounds how far back
+// lastPushQuery looks before the timing heuristic has guessed a push interval
+// (e.g. right after a group is first seen), since 2*refreshPeriod is a
+// reasonable stand-in expectation.
+const defaultFreshnessMultiplier = 2
 
-			if state.Update(timestamp) {
-				up := state.IsUp()
-				if up {
-					log.Printf("Group up: %v", key)
-				} else {
-					log.Printf("Group down: %v", key)
-				}
-				err := client.Upset(key, up)
-				if err != nil {
-					log.Printf("Error upsetting %s: %v", key, err)
-				}
+// updateState updates state for the group, preferring server-side truth from
+// Prometheus and falling back to the timing heuristic when Prometheus is
+// unconfigured or unreachable.
+func (a *app) updateState(ctx context.Context, key string, group pushgateway.MetricsGroup, state *tracking.GroupState, timestamp time.Time) {
+	if a.promClient != nil {
+		freshness := state.Timeout()
+		if freshness <= 0 {
+			freshness = defaultFreshnessMultiplier * a.refreshPeriod
+		}
+		query := lastPushQuery(group.Labels["job"], group.Labels["instance"], freshness)
+		samples, err := a.promClient.Query(ctx, query, time.Time{})
+		if err != nil {
+			log.Printf("Error querying Prometheus for %s, falling back to heuristic: %v", key, err)
+		} else {
+			up := false
+			if len(samples) > 0 {
+				lastPush := time.Unix(int64(samples[0].Value), 0)
+				up = time.Since(lastPush) < freshness
 			}
+			changed := state.UpdateFromPrometheus(timestamp, up)
+			a.applyUpDown(ctx, key, group, state.IsUp(), timestamp, changed)
+			return
 		}
+	}
 
-		for key := range states {
-			if !slices.Contains(receivedKeys, key) {
-				delete(states, key)
-				log.Printf("Group removed: %v", key)
-			}
+	changed := state.Update(timestamp)
+	a.applyUpDown(ctx, key, group, state.IsUp(), timestamp, changed)
+}
+
+// lastPushQuery builds a PromQL query for the highest push_time_seconds
+// value (the actual last-push time, as a Unix timestamp) that Prometheus
+// observed for job/instance within window.
+func lastPushQuery(job, instance string, window time.Duration) string {
+	return fmt.Sprintf(`max_over_time(push_time_seconds{job=%q,instance=%q}[%s])`, job, instance, window)
+}
+
+// applyUpDown pushes the up metric and logs the transition when the up
+// state changed this tick. It also (re-)notifies Alertmanager: once when
+// the state changes, and on every tick while the group is down, so the
+// alert's endsAt keeps being pushed out and doesn't auto-resolve while the
+// outage is ongoing.
+func (a *app) applyUpDown(ctx context.Context, key string, group pushgateway.MetricsGroup, up bool, lastSeen time.Time, changed bool) {
+	if changed {
+		if up {
+			log.Printf("Group up: %v", key)
+		} else {
+			log.Printf("Group down: %v", key)
+		}
+		if err := a.client.UpsetContext(ctx, key, up); err != nil {
+			log.Printf("Error upsetting %s: %v", key, err)
 		}
 	}
+
+	if !changed && up {
+		return
+	}
+	stateWord := "down"
+	if up {
+		stateWord = "up"
+	}
+	summary := fmt.Sprintf("Group %s is %s, last seen at %s", key, stateWord, lastSeen.Format(time.RFC3339))
+	a.notifyAlertmanager(ctx, key, group, summary, up)
+}
+
+// notifyAlertmanager pushes an alert reflecting the group's current up
+// state, resolving it immediately if up, otherwise leaving it firing for
+// alertEndsAtMargin. Called on every tick while a group is down, so endsAt
+// keeps being pushed out for the duration of the outage.
+func (a *app) notifyAlertmanager(ctx context.Context, key string, group pushgateway.MetricsGroup, summary string, up bool) {
+	if a.notifyClient == nil {
+		return
+	}
+
+	labels := make(map[string]string, len(group.Labels)+2)
+	for name, value := range group.Labels {
+		labels[name] = value
+	}
+	labels["alertname"] = "PushgatewayGroupDown"
+	labels["severity"] = "warning"
+
+	startsAt := time.Now()
+	endsAt := startsAt
+	if !up {
+		endsAt = startsAt.Add(alertEndsAtMargin)
+	}
+
+	alert := notify.Alert{
+		Labels:       labels,
+		Annotations:  map[string]string{"summary": summary},
+		StartsAt:     startsAt,
+		EndsAt:       endsAt,
+		GeneratorURL: a.client.GroupURL(key),
+	}
+	if err := a.notifyClient.PostAlerts(ctx, []notify.Alert{alert}); err != nil {
+		log.Printf("Error notifying Alertmanager for %s: %v", key, err)
+	}
 }
@@ -0,0 +1,72 @@
+// Package notify posts alerts to an Alertmanager instance.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Client posts alerts to an Alertmanager server.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+}
+
+// NewClient returns a Client for the Alertmanager server at baseURL.
+func NewClient(baseURL string) (Client, error) {
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return Client{}, fmt.Errorf("invalid baseURL: %w", err)
+	}
+	return Client{
+		baseURL:    parsedURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Alert is an Alertmanager alert, as accepted by the [Alerts API].
+//
+// [Alerts API]: https://github.com/prometheus/alertmanager/blob/main/api/v2/openapi.yaml
+type Alert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt,omitempty"`
+	EndsAt       time.Time         `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// PostAlerts sends alerts to Alertmanager's [Alerts API].
+//
+// [Alerts API]: https://github.com/prometheus/alertmanager/blob/main/api/v2/openapi.yaml
+func (c Client) PostAlerts(ctx context.Context, alerts []Alert) error {
+	payload, err := json.Marshal(alerts)
+	if err != nil {
+		return err
+	}
+	reqURL, err := c.baseURL.Parse("/api/v2/alerts")
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", reqURL.String(), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(ctx)
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		body, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("HTTP response: %s: %s", res.Status, body)
+	}
+	return nil
+}
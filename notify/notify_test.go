@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientPostAlerts(t *testing.T) {
+	var gotPath string
+	var gotAlerts []Alert
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotAlerts); err != nil {
+			t.Errorf("Expected valid JSON body, got error: %v", err)
+		}
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	startsAt := time.Now()
+	alert := Alert{
+		Labels:       map[string]string{"alertname": "PushgatewayGroupDown", "job": "foo"},
+		Annotations:  map[string]string{"summary": "Group foo is down"},
+		StartsAt:     startsAt,
+		GeneratorURL: "http://pushgateway/metrics/job/foo",
+	}
+
+	err = client.PostAlerts(context.Background(), []Alert{alert})
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if gotPath != "/api/v2/alerts" {
+		t.Errorf("Expected /api/v2/alerts path, got: %v", gotPath)
+	}
+	if len(gotAlerts) != 1 {
+		t.Fatalf("Expected one Alert, got: %v", len(gotAlerts))
+	}
+	if gotAlerts[0].Labels["job"] != "foo" {
+		t.Errorf("Expected job label foo, got: %v", gotAlerts[0].Labels)
+	}
+}
+
+func TestClientPostAlertsError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	err = client.PostAlerts(context.Background(), []Alert{{Labels: map[string]string{"alertname": "x"}}})
+	if err == nil {
+		t.Fatalf("Expected non-nil error.")
+	}
+}
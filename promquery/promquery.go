@@ -0,0 +1,157 @@
+// Package promquery provides read access to the Prometheus HTTP query API.
+package promquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Client queries a Prometheus server's instant and range query endpoints.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+}
+
+// NewClient returns a Client for the Prometheus server at baseURL.
+func NewClient(baseURL string) (Client, error) {
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return Client{}, fmt.Errorf("invalid baseURL: %w", err)
+	}
+	return Client{
+		baseURL:    parsedURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Sample is a single instant-query result: a labeled time series value at a
+// point in time.
+type Sample struct {
+	Metric    map[string]string
+	Timestamp time.Time
+	Value     float64
+}
+
+// Query runs an instant query against the Prometheus [Query API]. If ts is
+// the zero Time, Prometheus evaluates the query at the current time.
+//
+// [Query API]: https://prometheus.io/docs/prometheus/latest/querying/api/#instant-queries
+func (c Client) Query(ctx context.Context, query string, ts time.Time) ([]Sample, error) {
+	values := url.Values{"query": {query}}
+	if !ts.IsZero() {
+		values.Set("time", strconv.FormatInt(ts.Unix(), 10))
+	}
+	return c.do(ctx, "/api/v1/query", values)
+}
+
+// QueryRange runs a range query against the Prometheus [Query Range API].
+//
+// [Query Range API]: https://prometheus.io/docs/prometheus/latest/querying/api/#range-queries
+func (c Client) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]Sample, error) {
+	values := url.Values{
+		"query": {query},
+		"start": {strconv.FormatInt(start.Unix(), 10)},
+		"end":   {strconv.FormatInt(end.Unix(), 10)},
+		"step":  {strconv.FormatFloat(step.Seconds(), 'f', -1, 64)},
+	}
+	return c.do(ctx, "/api/v1/query_range", values)
+}
+
+func (c Client) do(ctx context.Context, path string, values url.Values) ([]Sample, error) {
+	reqURL, err := c.baseURL.Parse(path + "?" + values.Encode())
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("GET", reqURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	var envelope responseEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	if envelope.Status != "success" {
+		return nil, fmt.Errorf("query failed: %s: %s", envelope.ErrorType, envelope.Error)
+	}
+	return envelope.Data.samples()
+}
+
+type responseEnvelope struct {
+	Status    string     `json:"status"`
+	Data      resultData `json:"data"`
+	ErrorType string     `json:"errorType"`
+	Error     string     `json:"error"`
+}
+
+type resultData struct {
+	ResultType string      `json:"resultType"`
+	Result     []rawResult `json:"result"`
+}
+
+type rawResult struct {
+	Metric map[string]string `json:"metric"`
+	Value  rawValue          `json:"value"`
+	Values []rawValue        `json:"values"`
+}
+
+// rawValue is a Prometheus [timestamp, value] pair: a float64 Unix
+// timestamp paired with the sample value encoded as a string.
+type rawValue [2]interface{}
+
+func (d resultData) samples() ([]Sample, error) {
+	var samples []Sample
+	for _, result := range d.Result {
+		if d.ResultType == "matrix" {
+			for _, value := range result.Values {
+				sample, err := value.toSample(result.Metric)
+				if err != nil {
+					return nil, err
+				}
+				samples = append(samples, sample)
+			}
+		} else {
+			sample, err := result.Value.toSample(result.Metric)
+			if err != nil {
+				return nil, err
+			}
+			samples = append(samples, sample)
+		}
+	}
+	return samples, nil
+}
+
+func (v rawValue) toSample(metric map[string]string) (Sample, error) {
+	ts, ok := v[0].(float64)
+	if !ok {
+		return Sample{}, fmt.Errorf("invalid timestamp: %v", v[0])
+	}
+	valueString, ok := v[1].(string)
+	if !ok {
+		return Sample{}, fmt.Errorf("invalid value: %v", v[1])
+	}
+	value, err := strconv.ParseFloat(valueString, 64)
+	if err != nil {
+		return Sample{}, fmt.Errorf("invalid value: %w", err)
+	}
+	return Sample{
+		Metric:    metric,
+		Timestamp: time.UnixMilli(int64(ts * 1000)),
+		Value:     value,
+	}, nil
+}
@@ -0,0 +1,97 @@
+package promquery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientQuery(t *testing.T) {
+	var gotQuery string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			t.Logf("Expected query path, got: %v", r.URL.Path)
+			w.WriteHeader(400)
+			return
+		}
+		gotQuery = r.URL.Query().Get("query")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"status": "success",
+			"data": {
+				"resultType": "vector",
+				"result": [
+					{"metric": {"job": "foo"}, "value": [1700000000, "42"]}
+				]
+			}
+		}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	samples, err := client.Query(context.Background(), `up{job="foo"}`, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if gotQuery != `up{job="foo"}` {
+		t.Errorf("Expected query to be forwarded, got: %v", gotQuery)
+	}
+	if len(samples) != 1 {
+		t.Fatalf("Expected one Sample, got: %v", len(samples))
+	}
+	if samples[0].Metric["job"] != "foo" {
+		t.Errorf("Expected job label foo, got: %v", samples[0].Metric)
+	}
+	if samples[0].Value != 42 {
+		t.Errorf("Expected value 42, got: %v", samples[0].Value)
+	}
+	if !samples[0].Timestamp.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Expected timestamp 1700000000, got: %v", samples[0].Timestamp)
+	}
+}
+
+func TestClientQueryEmptyResult(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status": "success", "data": {"resultType": "vector", "result": []}}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	samples, err := client.Query(context.Background(), `up{job="foo"}`, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(samples) != 0 {
+		t.Errorf("Expected no samples, got: %v", len(samples))
+	}
+}
+
+func TestClientQueryError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status": "error", "errorType": "bad_data", "error": "invalid query"}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	_, err = client.Query(context.Background(), `{`, time.Time{})
+	if err == nil {
+		t.Fatalf("Expected non-nil error.")
+	}
+}
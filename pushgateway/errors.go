@@ -0,0 +1,37 @@
+package pushgateway
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrAPI is the sentinel error wrapped by every [APIError], so callers can
+// match any API failure with errors.Is regardless of status code.
+var ErrAPI = errors.New("pushgateway API error")
+
+// APIError is returned when the Pushgateway API responds with an
+// unexpected HTTP status.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+	Warnings   []string
+}
+
+func newAPIError(res *http.Response, body []byte, warnings []string) *APIError {
+	return &APIError{
+		StatusCode: res.StatusCode,
+		Status:     res.Status,
+		Body:       body,
+		Warnings:   warnings,
+	}
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("HTTP response: %s", e.Status)
+}
+
+func (e *APIError) Unwrap() error {
+	return ErrAPI
+}
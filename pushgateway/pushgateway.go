@@ -2,6 +2,8 @@
 package pushgateway
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +12,7 @@ import (
 	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Pushgateway reads and updates Pushgateway metrics.
@@ -43,10 +46,26 @@ func NewPushgateway(baseURL string) Pushgateway {
 	}
 }
 
+// GroupURL returns the absolute URL of key's metrics group, suitable for
+// linking back to the Pushgateway from elsewhere (e.g. an alert).
+func (p Pushgateway) GroupURL(key string) string {
+	url, err := p.baseURL.Parse("/metrics/" + key)
+	if err != nil {
+		return ""
+	}
+	return url.String()
+}
+
 // Delete deletes the metrics for key using a [DELETE request].
 //
 // [DELETE request]: https://github.com/prometheus/pushgateway#delete-method
 func (p Pushgateway) Delete(key string) error {
+	return p.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext is like [Pushgateway.Delete], but carries a context that can
+// cancel or time out the underlying request.
+func (p Pushgateway) DeleteContext(ctx context.Context, key string) error {
 	if !strings.HasPrefix(key, "job/") {
 		return fmt.Errorf("Key without job/ prefix.")
 	}
@@ -58,50 +77,101 @@ func (p Pushgateway) Delete(key string) error {
 	if err != nil {
 		return err
 	}
+	req = req.WithContext(ctx)
 	res, err := p.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
 	defer res.Body.Close()
 	if res.StatusCode != 202 {
-		return fmt.Errorf("HTTP response: %v", res.Status)
+		body, _ := io.ReadAll(res.Body)
+		return newAPIError(res, body, nil)
 	}
 	return nil
 }
 
+// DeleteGroup is like [Pushgateway.Delete], but builds the key from a
+// structured label set instead of a pre-built path, so labels other than job
+// containing "/" don't need manual encoding. A job value that needs base64
+// encoding yields a key without the "job/" prefix, so an error is returned.
+func (p Pushgateway) DeleteGroup(labels map[string]string) error {
+	return p.DeleteGroupContext(context.Background(), labels)
+}
+
+// DeleteGroupContext is like [Pushgateway.DeleteGroup], but carries a
+// context that can cancel or time out the underlying request.
+func (p Pushgateway) DeleteGroupContext(ctx context.Context, labels map[string]string) error {
+	key, err := groupKey(labels)
+	if err != nil {
+		return err
+	}
+	return p.DeleteContext(ctx, key)
+}
+
+// groupKey validates labels and returns the [MetricsGroup.Key] for it,
+// since labels comes from arbitrary external input and Key panics if the
+// job label is missing.
+func groupKey(labels map[string]string) (string, error) {
+	if labels["job"] == "" {
+		return "", fmt.Errorf("Missing job label.")
+	}
+	return MetricsGroup{Labels: labels}.Key(), nil
+}
+
 // QueryMetrics calls the [Query API] and returns MetricsGroup objects.
 //
 // [Query API]: https://github.com/prometheus/pushgateway#query-api
-func (p Pushgateway) QueryMetrics() ([]MetricsGroup, error) {
+func (p Pushgateway) QueryMetrics() ([]MetricsGroup, []string, error) {
+	return p.QueryMetricsContext(context.Background())
+}
+
+// QueryMetricsContext is like [Pushgateway.QueryMetrics], but carries a
+// context that can cancel or time out the underlying request.
+func (p Pushgateway) QueryMetricsContext(ctx context.Context) ([]MetricsGroup, []string, error) {
 	url, _ := p.baseURL.Parse("/api/v1/metrics")
-	res, err := p.httpClient.Get(url.String())
+	req, err := http.NewRequest("GET", url.String(), nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer res.Body.Close()
-	if res.StatusCode != 200 {
-		return nil, fmt.Errorf("HTTP response: %v", res.Status)
+	req = req.WithContext(ctx)
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
 	}
+	defer res.Body.Close()
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	var object map[string]interface{}
-	err = json.Unmarshal(body, &object)
-	if err != nil {
-		return nil, err
+	unmarshalErr := json.Unmarshal(body, &object)
+	var warnings []string
+	if unmarshalErr == nil {
+		warnings = parseWarnings(object)
+	}
+	if res.StatusCode != 200 {
+		return nil, nil, newAPIError(res, body, warnings)
+	}
+	if unmarshalErr != nil {
+		return nil, nil, fmt.Errorf("decoding response: %w", unmarshalErr)
 	}
 	groups, err := parseMetricsGroups(object)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return groups, nil
+	return groups, warnings, nil
 }
 
 // Upset pushes the up metric for key, using a [PUT request] when up is false.
 //
 // [PUT request]: https://github.com/prometheus/pushgateway#put-method
 func (p Pushgateway) Upset(key string, up bool) error {
+	return p.UpsetContext(context.Background(), key, up)
+}
+
+// UpsetContext is like [Pushgateway.Upset], but carries a context that can
+// cancel or time out the underlying request.
+func (p Pushgateway) UpsetContext(ctx context.Context, key string, up bool) error {
 	if !strings.HasPrefix(key, "job/") {
 		return fmt.Errorf("Key without job/ prefix.")
 	}
@@ -118,21 +188,41 @@ func (p Pushgateway) Upset(key string, up bool) error {
 	if err != nil {
 		return err
 	}
+	req = req.WithContext(ctx)
 	res, err := p.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
 	defer res.Body.Close()
 	if res.StatusCode != 200 {
-		return fmt.Errorf("HTTP response: %v", res.Status)
+		body, _ := io.ReadAll(res.Body)
+		return newAPIError(res, body, nil)
 	}
 	return nil
 }
 
-// TODO Base64 encoding
-//
+// UpsetGroup is like [Pushgateway.Upset], but builds the key from a
+// structured label set instead of a pre-built path, so labels other than job
+// containing "/" don't need manual encoding. A job value that needs base64
+// encoding yields a key without the "job/" prefix, so an error is returned.
+func (p Pushgateway) UpsetGroup(labels map[string]string, up bool) error {
+	return p.UpsetGroupContext(context.Background(), labels, up)
+}
+
+// UpsetGroupContext is like [Pushgateway.UpsetGroup], but carries a context
+// that can cancel or time out the underlying request.
+func (p Pushgateway) UpsetGroupContext(ctx context.Context, labels map[string]string, up bool) error {
+	key, err := groupKey(labels)
+	if err != nil {
+		return err
+	}
+	return p.UpsetContext(ctx, key, up)
+}
+
 // Key returns a path containing label names and values, starting with "job".
-// This can be used in Pushgateway [URLs].
+// This can be used in Pushgateway [URLs]. Label values containing "/",
+// non-ASCII bytes, or the empty string are base64-encoded, per the URL
+// grouping key spec.
 //
 // [URLs]: https://github.com/prometheus/pushgateway#url
 func (g MetricsGroup) Key() string {
@@ -147,12 +237,35 @@ func (g MetricsGroup) Key() string {
 		}
 	}
 	slices.Sort(names)
-	parts := make([]string, 0, len(g.Labels)*2)
-	parts = append(parts, "job", job)
+	segments := make([]string, 0, len(g.Labels))
+	segments = append(segments, encodeLabelSegment("job", job))
 	for _, name := range names {
-		parts = append(parts, name, g.Labels[name])
+		segments = append(segments, encodeLabelSegment(name, g.Labels[name]))
+	}
+	return strings.Join(segments, "/")
+}
+
+// encodeLabelSegment returns the "name/value" or "name@base64/value" path
+// segment for a single label.
+func encodeLabelSegment(name, value string) string {
+	if needsBase64Encoding(value) {
+		return name + "@base64/" + base64.RawURLEncoding.EncodeToString([]byte(value))
 	}
-	return strings.Join(parts, "/")
+	return name + "/" + value
+}
+
+// needsBase64Encoding returns true if value can't be used as a plain path
+// segment: it's empty, contains a "/", or contains non-ASCII bytes.
+func needsBase64Encoding(value string) bool {
+	if value == "" {
+		return true
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] == '/' || value[i] >= utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
 }
 
 // LabelNamesMatch returns true if the group label and provided names match.
@@ -200,32 +313,38 @@ func (m Metrics) MinTimestamp() time.Time {
 	return slices.MinFunc(timestamps, func(a, b time.Time) int { return a.Compare(b) })
 }
 
-// parse... digs the relevant data out of the generic JSON object.
+// parse... digs the relevant data out of the generic JSON object. They
+// return errors rather than panicking, since object comes from an untrusted
+// Pushgateway response.
 
 func parseMetricsGroups(object map[string]interface{}) ([]MetricsGroup, error) {
 	status, ok := object["status"].(string)
 	if !ok {
-		panic(fmt.Sprintf("Invalid status attribute: %v", object["status"]))
+		return nil, fmt.Errorf("Invalid status attribute: %v", object["status"])
 	}
 	if status != "success" {
 		return nil, fmt.Errorf("Status attribute: %s", status)
 	}
 	data, ok := object["data"].([]interface{})
 	if !ok {
-		panic(fmt.Sprintf("Invalid data attribute: %v", object["data"]))
+		return nil, fmt.Errorf("Invalid data attribute: %v", object["data"])
 	}
 	groups := make([]MetricsGroup, 0, len(data))
 	for _, item := range data {
 		group, ok := item.(map[string]interface{})
 		if !ok {
-			panic(fmt.Sprintf("Invalid data array item: %v", item))
+			return nil, fmt.Errorf("Invalid data array item: %v", item)
+		}
+		parsed, err := parseMetricsGroup(group)
+		if err != nil {
+			return nil, err
 		}
-		groups = append(groups, parseMetricsGroup(group))
+		groups = append(groups, parsed)
 	}
 	return groups, nil
 }
 
-func parseMetricsGroup(data map[string]interface{}) MetricsGroup {
+func parseMetricsGroup(data map[string]interface{}) (MetricsGroup, error) {
 	var labels map[string]string
 	metrics := Metrics{}
 	for k, v := range data {
@@ -234,34 +353,56 @@ func parseMetricsGroup(data map[string]interface{}) MetricsGroup {
 			continue
 		}
 		if k == "labels" {
-			labels = parseLabels(v_map)
+			parsed, err := parseLabels(v_map)
+			if err != nil {
+				return MetricsGroup{}, err
+			}
+			labels = parsed
 		} else {
-			metrics[k] = parseMetric(v_map)
+			metric, err := parseMetric(v_map)
+			if err != nil {
+				return MetricsGroup{}, err
+			}
+			metrics[k] = metric
 		}
 	}
-	return MetricsGroup{Labels: labels, Metrics: metrics}
+	return MetricsGroup{Labels: labels, Metrics: metrics}, nil
 }
 
-func parseLabels(data map[string]interface{}) map[string]string {
+func parseLabels(data map[string]interface{}) (map[string]string, error) {
 	labels := make(map[string]string, len(data))
 	for k, v := range data {
 		v_string, ok := v.(string)
 		if !ok {
-			panic(fmt.Sprintf("Invalid labels object: %v", data))
+			return nil, fmt.Errorf("Invalid labels object: %v", data)
 		}
 		labels[k] = v_string
 	}
-	return labels
+	return labels, nil
+}
+
+func parseWarnings(object map[string]interface{}) []string {
+	items, ok := object["warnings"].([]interface{})
+	if !ok {
+		return nil
+	}
+	warnings := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			warnings = append(warnings, s)
+		}
+	}
+	return warnings
 }
 
-func parseMetric(data map[string]interface{}) Metric {
+func parseMetric(data map[string]interface{}) (Metric, error) {
 	timestamp_string, ok := data["time_stamp"].(string)
 	if !ok {
-		panic(fmt.Sprintf("Invalid time_stamp attribute: %v", data["time_stamp"]))
+		return Metric{}, fmt.Errorf("Invalid time_stamp attribute: %v", data["time_stamp"])
 	}
 	timestamp, err := time.Parse(time.RFC3339, timestamp_string)
 	if err != nil {
-		panic(fmt.Sprintf("Invalid time_stamp attribute: %v", err))
+		return Metric{}, fmt.Errorf("Invalid time_stamp attribute: %w", err)
 	}
-	return Metric{Timestamp: timestamp}
+	return Metric{Timestamp: timestamp}, nil
 }
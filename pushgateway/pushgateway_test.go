@@ -2,6 +2,7 @@ package pushgateway
 
 import (
 	_ "embed"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -42,6 +43,43 @@ func TestMetricsGroup(t *testing.T) {
 	}
 }
 
+func TestMetricsGroupKeyBase64(t *testing.T) {
+	cases := []struct {
+		name   string
+		labels map[string]string
+		want   string
+	}{
+		{
+			name:   "slash in value",
+			labels: map[string]string{"job": "foo", "instance": "/dev/null"},
+			want:   "job/foo/instance@base64/L2Rldi9udWxs",
+		},
+		{
+			name:   "empty value",
+			labels: map[string]string{"job": "foo", "instance": ""},
+			want:   "job/foo/instance@base64/",
+		},
+		{
+			name:   "multi-byte UTF-8 value",
+			labels: map[string]string{"job": "foo", "instance": "héllo"},
+			want:   "job/foo/instance@base64/aMOpbGxv",
+		},
+		{
+			name:   "slash in job value",
+			labels: map[string]string{"job": "foo/bar"},
+			want:   "job@base64/Zm9vL2Jhcg",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			group := MetricsGroup{Labels: c.labels}
+			if got := group.Key(); got != c.want {
+				t.Errorf("Expected key: %v, got: %v", c.want, got)
+			}
+		})
+	}
+}
+
 func TestMetricsMinTimestamp(t *testing.T) {
 	if timestamp := group.Metrics.MinTimestamp(); timestamp != earlier {
 		t.Errorf("Expected timestamp: %v, got: %v", earlier, timestamp)
@@ -71,6 +109,14 @@ func TestMetricsMaxTimestamp(t *testing.T) {
 	}
 }
 
+func TestPushgatewayGroupURL(t *testing.T) {
+	client := NewPushgateway("http://localhost:9091")
+	expected := "http://localhost:9091/metrics/job/test"
+	if actual := client.GroupURL("job/test"); actual != expected {
+		t.Errorf("Expected URL: %v, got: %v", expected, actual)
+	}
+}
+
 func TestPushgatewayDelete(t *testing.T) {
 	var method, body string
 
@@ -110,6 +156,68 @@ func TestPushgatewayDelete(t *testing.T) {
 	}
 }
 
+func TestPushgatewayDeleteGroup(t *testing.T) {
+	var gotPath string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(202)
+	}))
+	defer ts.Close()
+
+	client := NewPushgateway(ts.URL)
+	labels := map[string]string{"job": "foo", "instance": "/dev/null"}
+
+	err := client.DeleteGroup(labels)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expectedPath := "/metrics/" + MetricsGroup{Labels: labels}.Key()
+	if gotPath != expectedPath {
+		t.Errorf("Expected path: %v, got: %v", expectedPath, gotPath)
+	}
+}
+
+func TestPushgatewayUpsetGroup(t *testing.T) {
+	var gotPath, gotMethod string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotMethod = r.Method
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	client := NewPushgateway(ts.URL)
+	labels := map[string]string{"job": "foo", "instance": "/dev/null"}
+
+	err := client.UpsetGroup(labels, true)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expectedPath := "/metrics/" + MetricsGroup{Labels: labels}.Key()
+	if gotPath != expectedPath {
+		t.Errorf("Expected path: %v, got: %v", expectedPath, gotPath)
+	}
+	if gotMethod != "POST" {
+		t.Errorf("Expected POST method, got: %v", gotMethod)
+	}
+}
+
+func TestPushgatewayDeleteGroupMissingJob(t *testing.T) {
+	client := NewPushgateway("http://localhost:9091")
+	if err := client.DeleteGroup(map[string]string{"instance": "foo"}); err == nil {
+		t.Fatalf("Expected non-nil error.")
+	}
+}
+
+func TestPushgatewayUpsetGroupMissingJob(t *testing.T) {
+	client := NewPushgateway("http://localhost:9091")
+	if err := client.UpsetGroup(map[string]string{"instance": "foo"}, true); err == nil {
+		t.Fatalf("Expected non-nil error.")
+	}
+}
+
 //go:embed testdata/metrics.json
 var metrics []byte
 
@@ -131,13 +239,16 @@ func TestPushgatewayQueryMetrics(t *testing.T) {
 	defer ts.Close()
 
 	client := NewPushgateway(ts.URL)
-	groups, err := client.QueryMetrics()
+	groups, warnings, err := client.QueryMetrics()
 	if err != nil {
 		t.Fatalf("Expected nil error, got: %v", err)
 	}
 	if len(groups) != 1 {
 		t.Fatalf("Expected one MetricsGroup, got: %v", len(groups))
 	}
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got: %v", warnings)
+	}
 
 	group := groups[0]
 	if !group.LabelNamesMatch("job", "instance") {
@@ -150,12 +261,84 @@ func TestPushgatewayQueryMetrics(t *testing.T) {
 	}
 
 	body = metricsError
-	_, err = client.QueryMetrics()
+	_, _, err = client.QueryMetrics()
 	if err == nil {
 		t.Errorf("Expected non-nil error.")
 	}
 }
 
+func TestPushgatewayQueryMetricsMalformed(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+	}{
+		{"non-string status", `{"status": 1}`},
+		{"non-array data", `{"status": "success", "data": "oops"}`},
+		{"non-object data item", `{"status": "success", "data": ["oops"]}`},
+		{"non-string label value", `{"status": "success", "data": [{"labels": {"job": 1}}]}`},
+		{"missing time_stamp", `{"status": "success", "data": [{"labels": {"job": "foo"}, "up": {}}]}`},
+		{"invalid time_stamp", `{"status": "success", "data": [{"labels": {"job": "foo"}, "up": {"time_stamp": "not a time"}}]}`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(200)
+				w.Write([]byte(c.body))
+			}))
+			defer ts.Close()
+
+			client := NewPushgateway(ts.URL)
+			_, _, err := client.QueryMetrics()
+			if err == nil {
+				t.Fatalf("Expected non-nil error.")
+			}
+		})
+	}
+}
+
+func TestPushgatewayQueryMetricsAPIError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+		w.Write([]byte("internal error"))
+	}))
+	defer ts.Close()
+
+	client := NewPushgateway(ts.URL)
+	_, _, err := client.QueryMetrics()
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got: %v", err)
+	}
+	if apiErr.StatusCode != 500 {
+		t.Errorf("Expected status code 500, got: %v", apiErr.StatusCode)
+	}
+	if string(apiErr.Body) != "internal error" {
+		t.Errorf("Expected body %q, got: %q", "internal error", apiErr.Body)
+	}
+	if !errors.Is(err, ErrAPI) {
+		t.Errorf("Expected errors.Is(err, ErrAPI) to be true.")
+	}
+}
+
+func TestPushgatewayQueryMetricsInvalidJSON(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.Write([]byte("not json at all"))
+	}))
+	defer ts.Close()
+
+	client := NewPushgateway(ts.URL)
+	_, _, err := client.QueryMetrics()
+	if err == nil {
+		t.Fatalf("Expected non-nil error.")
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		t.Fatalf("Expected a plain decode error, got: %v", err)
+	}
+}
+
 func TestPushgatewayUpset(t *testing.T) {
 	var method, body string
 
@@ -0,0 +1,95 @@
+package pushgateway
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// BasicAuth holds HTTP basic auth credentials.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Config configures the HTTP transport used by a [Pushgateway] client.
+type Config struct {
+	// BasicAuth credentials, attached to every request if set. A bearer
+	// token, if also configured, replaces them in the Authorization header.
+	BasicAuth *BasicAuth
+
+	// BearerToken, attached to every request as an Authorization header
+	// if set. Ignored if BearerTokenFile is set.
+	BearerToken string
+	// BearerTokenFile is the path to a file containing a bearer token,
+	// read once when the client is constructed.
+	BearerTokenFile string
+
+	// TLSConfig configures the TLS settings of the default transport.
+	// Ignored if RoundTripper is set.
+	TLSConfig *tls.Config
+
+	// RoundTripper overrides the default transport entirely. BasicAuth
+	// and the bearer token are still attached on top of it.
+	RoundTripper http.RoundTripper
+}
+
+// NewPushgatewayWithConfig is like [NewPushgateway], but accepts a [Config]
+// for authentication and transport-level settings.
+func NewPushgatewayWithConfig(baseURL string, config Config) (Pushgateway, error) {
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return Pushgateway{}, fmt.Errorf("invalid baseURL: %w", err)
+	}
+
+	bearerToken := config.BearerToken
+	if config.BearerTokenFile != "" {
+		data, err := os.ReadFile(config.BearerTokenFile)
+		if err != nil {
+			return Pushgateway{}, fmt.Errorf("reading bearer token file: %w", err)
+		}
+		bearerToken = strings.TrimSpace(string(data))
+	}
+
+	next := config.RoundTripper
+	if next == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = config.TLSConfig
+		next = transport
+	}
+
+	return Pushgateway{
+		baseURL: parsedURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &authTransport{
+				next:        next,
+				basicAuth:   config.BasicAuth,
+				bearerToken: bearerToken,
+			},
+		},
+	}, nil
+}
+
+// authTransport attaches the configured credentials to every request before
+// delegating to the wrapped RoundTripper.
+type authTransport struct {
+	next        http.RoundTripper
+	basicAuth   *BasicAuth
+	bearerToken string
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if t.basicAuth != nil {
+		req.SetBasicAuth(t.basicAuth.Username, t.basicAuth.Password)
+	}
+	if t.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+t.bearerToken)
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,89 @@
+package pushgateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestNewPushgatewayWithConfigBasicAuth(t *testing.T) {
+	var gotUsername, gotPassword string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUsername, gotPassword, _ = r.BasicAuth()
+		w.WriteHeader(202)
+	}))
+	defer ts.Close()
+
+	client, err := NewPushgatewayWithConfig(ts.URL, Config{
+		BasicAuth: &BasicAuth{Username: "user", Password: "pass"},
+	})
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	if err := client.Delete("job/test"); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if gotUsername != "user" || gotPassword != "pass" {
+		t.Errorf("Expected basic auth user/pass, got: %v/%v", gotUsername, gotPassword)
+	}
+}
+
+func TestNewPushgatewayWithConfigBearerToken(t *testing.T) {
+	var gotAuth string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(202)
+	}))
+	defer ts.Close()
+
+	client, err := NewPushgatewayWithConfig(ts.URL, Config{BearerToken: "token123"})
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	if err := client.Delete("job/test"); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if gotAuth != "Bearer token123" {
+		t.Errorf("Expected Bearer token123, got: %v", gotAuth)
+	}
+}
+
+func TestNewPushgatewayWithConfigBearerTokenFile(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "token")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if _, err := file.WriteString("filetoken\n"); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	file.Close()
+
+	var gotAuth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(202)
+	}))
+	defer ts.Close()
+
+	client, err := NewPushgatewayWithConfig(ts.URL, Config{BearerTokenFile: file.Name()})
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	if err := client.Delete("job/test"); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if gotAuth != "Bearer filetoken" {
+		t.Errorf("Expected Bearer filetoken, got: %v", gotAuth)
+	}
+
+	_, err = NewPushgatewayWithConfig(ts.URL, Config{BearerTokenFile: "/nonexistent"})
+	if err == nil {
+		t.Fatalf("Expected non-nil error.")
+	}
+}
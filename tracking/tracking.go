@@ -5,31 +5,81 @@ import (
 	"time"
 )
 
+// Source identifies how a GroupState's up state was determined.
+type Source int
+
+const (
+	// SourceHeuristic means the up state is derived from the group's own
+	// timing history, guessing a timeout from the observed push interval.
+	SourceHeuristic Source = iota
+	// SourcePrometheus means the up state was confirmed by a query
+	// against the Prometheus server tracking the group.
+	SourcePrometheus
+)
+
 // GroupState contains the up state and timing data of a metrics group.
 type GroupState struct {
 	up        bool
 	timestamp time.Time
 	timeout   time.Duration
+	source    Source
 }
 
 func NewGroupState(timestamp time.Time) *GroupState {
 	return &GroupState{timestamp: timestamp}
 }
 
-// Update returns true if the up state changed during the update.
+// Update returns true if the up state changed during the update, deciding
+// the up state with the timing heuristic. The source is set to
+// SourceHeuristic.
 func (gs *GroupState) Update(timestamp time.Time) bool {
 	wasUp := gs.up
+	gs.updateTimestamp(timestamp)
+	gs.source = SourceHeuristic
+	gs.up = gs.isUpHeuristic()
+	return wasUp != gs.up
+}
+
+// UpdateFromPrometheus returns true if the up state changed, deciding the
+// up state from up, as confirmed by a Prometheus query rather than the
+// timing heuristic. The source is set to SourcePrometheus.
+func (gs *GroupState) UpdateFromPrometheus(timestamp time.Time, up bool) bool {
+	wasUp := gs.up
+	gs.updateTimestamp(timestamp)
+	gs.source = SourcePrometheus
+	gs.up = up
+	return wasUp != gs.up
+}
+
+func (gs *GroupState) updateTimestamp(timestamp time.Time) {
 	if timestamp.After(gs.timestamp) && !gs.timestamp.IsZero() {
 		delta := timestamp.Sub(gs.timestamp)
 		gs.timeout = delta + delta/2
 	}
 	gs.timestamp = timestamp
-	gs.up = gs.IsUp()
-	return wasUp != gs.up
 }
 
 // IsUp returns the up state at the current time.
 func (gs *GroupState) IsUp() bool {
+	if gs.source == SourcePrometheus {
+		return gs.up
+	}
+	return gs.isUpHeuristic()
+}
+
+// Source returns how the up state was last determined.
+func (gs *GroupState) Source() Source {
+	return gs.source
+}
+
+// Timeout returns the currently guessed push interval timeout, as computed
+// by the timing heuristic. It is zero until a later timestamp than the
+// previous one has been observed by Update or UpdateFromPrometheus.
+func (gs *GroupState) Timeout() time.Duration {
+	return gs.timeout
+}
+
+func (gs *GroupState) isUpHeuristic() bool {
 	if gs.timestamp.IsZero() || gs.timeout == 0 {
 		return false
 	}
@@ -21,6 +21,9 @@ func TestGroupState(t *testing.T) {
 	if !state.IsUp() {
 		t.Errorf("Expected up state to be true.")
 	}
+	if state.Timeout() != state.timeout {
+		t.Errorf("Expected Timeout(): %v, got: %v", state.timeout, state.Timeout())
+	}
 }
 
 func TestGroupStateDown(t *testing.T) {
@@ -31,3 +34,24 @@ func TestGroupStateDown(t *testing.T) {
 		t.Errorf("Expected up state to be false.")
 	}
 }
+
+func TestGroupStateUpdateFromPrometheus(t *testing.T) {
+	base := time.Now().Add(-time.Hour)
+	state := NewGroupState(base)
+	if changed := state.UpdateFromPrometheus(base.Add(time.Minute), true); !changed {
+		t.Errorf("Expected state to change.")
+	}
+	if !state.IsUp() {
+		t.Errorf("Expected up state to be true.")
+	}
+	if state.Source() != SourcePrometheus {
+		t.Errorf("Expected source to be SourcePrometheus, got: %v", state.Source())
+	}
+
+	if changed := state.UpdateFromPrometheus(base.Add(2*time.Minute), false); !changed {
+		t.Errorf("Expected state to change.")
+	}
+	if state.IsUp() {
+		t.Errorf("Expected up state to be false.")
+	}
+}